@@ -0,0 +1,62 @@
+package scs_gin_adapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BenchmarkCommitPerCall simulates the pre-chunk0-3 behaviour of committing
+// after every mutating helper call, by calling Commit explicitly after each
+// Put in a handler with N session writes.
+func BenchmarkCommitPerCall(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+
+	ga := New(newTestSessionManager(newCountingStore()))
+
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	engine.Use(ga.LoadAndSave)
+	engine.GET("/", func(c *gin.Context) {
+		for i := 0; i < 10; i++ {
+			ga.Put(c, "key", i)
+			if _, _, err := ga.Commit(c); err != nil {
+				b.Fatalf("Commit: %v", err)
+			}
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		engine.ServeHTTP(rec, req)
+	}
+}
+
+// BenchmarkDeferredCommit exercises the same 10 mutating Puts per request,
+// but relying on LoadAndSave's deferred commit instead of committing after
+// every call, to substantiate the reduction in per-request store writes.
+func BenchmarkDeferredCommit(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+
+	ga := New(newTestSessionManager(newCountingStore()))
+
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	engine.Use(ga.LoadAndSave)
+	engine.GET("/", func(c *gin.Context) {
+		for i := 0; i < 10; i++ {
+			ga.Put(c, "key", i)
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		engine.ServeHTTP(rec, req)
+	}
+}
@@ -0,0 +1,69 @@
+package scs_gin_adapter
+
+import (
+	"bufio"
+	"net"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// commitResponseWriter wraps a gin.ResponseWriter so that an arbitrary
+// commit function runs exactly once, the moment the response actually
+// starts being written — on the first WriteHeader/Write/WriteString, or on
+// Hijack for connections the handler takes over itself (e.g. websockets).
+// This lets LoadAndSave defer the session commit until it is known no more
+// session mutations are coming, instead of committing on every mutating
+// helper call.
+type commitResponseWriter struct {
+	gin.ResponseWriter
+	once   sync.Once
+	commit func()
+}
+
+func (w *commitResponseWriter) commitOnce() {
+	w.once.Do(w.commit)
+}
+
+// WriteHeader implements gin.ResponseWriter.
+func (w *commitResponseWriter) WriteHeader(code int) {
+	w.commitOnce()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// WriteHeaderNow implements gin.ResponseWriter.
+func (w *commitResponseWriter) WriteHeaderNow() {
+	w.commitOnce()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write implements gin.ResponseWriter.
+func (w *commitResponseWriter) Write(data []byte) (int, error) {
+	w.commitOnce()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString implements gin.ResponseWriter.
+func (w *commitResponseWriter) WriteString(s string) (int, error) {
+	w.commitOnce()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush implements http.Flusher. gin.ResponseWriter's own Flush calls its
+// WriteHeaderNow directly rather than going through this wrapper's
+// WriteHeader/WriteHeaderNow, so without this override a handler that
+// flushes before its first Write (a streamed response whose first step
+// produces no output, for instance) would send headers with the session
+// never committed.
+func (w *commitResponseWriter) Flush() {
+	w.commitOnce()
+	w.ResponseWriter.Flush()
+}
+
+// Hijack implements http.Hijacker. The commit runs before control of the
+// connection is handed over, since no further Write will pass through this
+// wrapper afterwards.
+func (w *commitResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.commitOnce()
+	return w.ResponseWriter.Hijack()
+}
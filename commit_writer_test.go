@@ -0,0 +1,104 @@
+package scs_gin_adapter
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// hijackableRecorder adds a no-op http.Hijacker to httptest.ResponseRecorder,
+// which doesn't implement one itself. gin's own Hijack asserts its wrapped
+// ResponseWriter implements http.Hijacker, so this is needed to exercise it.
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	client, server := net.Pipe()
+	server.Close()
+	return client, bufio.NewReadWriter(bufio.NewReader(client), bufio.NewWriter(client)), nil
+}
+
+func newTestCommitWriter(rw http.ResponseWriter) (*commitResponseWriter, *int) {
+	gin.SetMode(gin.TestMode)
+	ginCtx, _ := gin.CreateTestContext(rw)
+	calls := 0
+	return &commitResponseWriter{
+		ResponseWriter: ginCtx.Writer,
+		commit:         func() { calls++ },
+	}, &calls
+}
+
+func TestCommitResponseWriterWriteHeaderCommitsOnce(t *testing.T) {
+	w, calls := newTestCommitWriter(httptest.NewRecorder())
+
+	w.WriteHeader(http.StatusTeapot)
+	w.WriteHeader(http.StatusTeapot)
+
+	if *calls != 1 {
+		t.Fatalf("want 1 commit, got %d", *calls)
+	}
+}
+
+func TestCommitResponseWriterWriteCommitsOnce(t *testing.T) {
+	w, calls := newTestCommitWriter(httptest.NewRecorder())
+
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := w.Write([]byte("again")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if *calls != 1 {
+		t.Fatalf("want 1 commit, got %d", *calls)
+	}
+}
+
+func TestCommitResponseWriterWriteStringCommitsOnce(t *testing.T) {
+	w, calls := newTestCommitWriter(httptest.NewRecorder())
+
+	if _, err := w.WriteString("hello"); err != nil {
+		t.Fatalf("WriteString: %v", err)
+	}
+
+	if *calls != 1 {
+		t.Fatalf("want 1 commit, got %d", *calls)
+	}
+}
+
+func TestCommitResponseWriterFlushCommitsBeforeFirstWrite(t *testing.T) {
+	w, calls := newTestCommitWriter(httptest.NewRecorder())
+
+	w.Flush()
+
+	if *calls != 1 {
+		t.Fatalf("want Flush to trigger the deferred commit, got %d calls", *calls)
+	}
+
+	// A later Write must not commit again.
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if *calls != 1 {
+		t.Fatalf("want still 1 commit after Write following Flush, got %d", *calls)
+	}
+}
+
+func TestCommitResponseWriterHijackCommitsBeforeHandoff(t *testing.T) {
+	w, calls := newTestCommitWriter(&hijackableRecorder{httptest.NewRecorder()})
+
+	conn, _, err := w.Hijack()
+	if err != nil {
+		t.Fatalf("Hijack: %v", err)
+	}
+	defer conn.Close()
+
+	if *calls != 1 {
+		t.Fatalf("want 1 commit, got %d", *calls)
+	}
+}
@@ -0,0 +1,111 @@
+package scs_gin_adapter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// flashPrefix namespaces flash entries within the underlying SCS session,
+// so they can live alongside ordinary session data without a key collision.
+const flashPrefix = "_flash_"
+
+// Flash stores a value under key that survives exactly one subsequent
+// request: it is cleared automatically the first time it is read via
+// GetFlash, GetFlashString or Flashes, just before that request's session
+// commit.
+func (ga *GinAdapter) Flash(ctx *gin.Context, key string, val interface{}) {
+	ga.sm.Put(ctx.Request.Context(), flashKey(key), val)
+}
+
+// GetFlash returns the flash value for key, or nil if it is not present. The
+// return value has the type interface{} so will usually need to be type
+// asserted before use; see GetFlashString for the common string case.
+func (ga *GinAdapter) GetFlash(ctx *gin.Context, key string) interface{} {
+	val := ga.sm.Get(ctx.Request.Context(), flashKey(key))
+	ga.markFlashRead(ctx, key)
+	return val
+}
+
+// GetFlashString returns the flash value for key as a string. The zero
+// value for a string ("") is returned if key does not exist or the value
+// could not be type asserted to a string.
+func (ga *GinAdapter) GetFlashString(ctx *gin.Context, key string) string {
+	val := ga.sm.GetString(ctx.Request.Context(), flashKey(key))
+	ga.markFlashRead(ctx, key)
+	return val
+}
+
+// HasFlash returns true if a flash value is present for key. Checking
+// HasFlash does not itself clear the flash entry.
+func (ga *GinAdapter) HasFlash(ctx *gin.Context, key string) bool {
+	return ga.sm.Exists(ctx.Request.Context(), flashKey(key))
+}
+
+// Flashes returns every pending flash entry, keyed by name with the
+// flashPrefix stripped off. All entries returned are cleared just before
+// this request's session commit.
+func (ga *GinAdapter) Flashes(ctx *gin.Context) map[string]interface{} {
+	flashes := make(map[string]interface{})
+	for _, k := range ga.sm.Keys(ctx.Request.Context()) {
+		if !strings.HasPrefix(k, flashPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(k, flashPrefix)
+		flashes[name] = ga.sm.Get(ctx.Request.Context(), k)
+		ga.markFlashRead(ctx, name)
+	}
+	return flashes
+}
+
+// ClearFlashes discards every pending flash entry immediately, without
+// waiting for it to be read.
+func (ga *GinAdapter) ClearFlashes(ctx *gin.Context) {
+	for _, k := range ga.sm.Keys(ctx.Request.Context()) {
+		if strings.HasPrefix(k, flashPrefix) {
+			ga.sm.Remove(ctx.Request.Context(), k)
+		}
+	}
+}
+
+func flashKey(key string) string {
+	return flashPrefix + key
+}
+
+// flashReadContextKey returns the gin.Context key used to track which flash
+// entries this adapter has read during the current request. It is scoped to
+// ga's own SessionManager so that the primary adapter and any sub-sessions
+// registered via Register don't clobber each other's read-tracking.
+func (ga *GinAdapter) flashReadContextKey() string {
+	return fmt.Sprintf("scs_gin_adapter.flash_read.%p", ga.sm)
+}
+
+func (ga *GinAdapter) markFlashRead(ctx *gin.Context, key string) {
+	read := map[string]struct{}{}
+	if existing, ok := ctx.Get(ga.flashReadContextKey()); ok {
+		if set, ok := existing.(map[string]struct{}); ok {
+			read = set
+		}
+	}
+	read[key] = struct{}{}
+	ctx.Set(ga.flashReadContextKey(), read)
+}
+
+// clearReadFlashes removes any flash entries GetFlash, GetFlashString or
+// Flashes read during this request. It is called from commitOne just before
+// the session is committed, so a flash survives exactly one request.
+func (ga *GinAdapter) clearReadFlashes(ginCtx *gin.Context, ctx context.Context) {
+	existing, ok := ginCtx.Get(ga.flashReadContextKey())
+	if !ok {
+		return
+	}
+	read, ok := existing.(map[string]struct{})
+	if !ok {
+		return
+	}
+	for key := range read {
+		ga.sm.Remove(ctx, flashKey(key))
+	}
+}
@@ -0,0 +1,137 @@
+package scs_gin_adapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestFlashExpiresAfterOneRead exercises Flash end to end across two
+// requests sharing a cookie jar: a value set on request one must still be
+// readable on request two (it survives exactly one subsequent request), but
+// gone by request three since reading it on request two marks it for
+// removal just before that request's commit.
+func TestFlashExpiresAfterOneRead(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ga := New(newTestSessionManager(newCountingStore()))
+
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	engine.Use(ga.LoadAndSave)
+
+	var readValues []string
+	engine.GET("/set", func(c *gin.Context) {
+		ga.Flash(c, "notice", "saved!")
+		c.String(http.StatusOK, "ok")
+	})
+	engine.GET("/read", func(c *gin.Context) {
+		readValues = append(readValues, ga.GetFlashString(c, "notice"))
+		c.String(http.StatusOK, "ok")
+	})
+
+	var cookie *http.Cookie
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/set", nil)
+	engine.ServeHTTP(rec, req)
+	for _, c := range rec.Result().Cookies() {
+		cookie = c
+	}
+	if cookie == nil {
+		t.Fatal("want a session cookie after setting a flash")
+	}
+
+	// First read: the flash was set last request, so it must still be there.
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/read", nil)
+	req.AddCookie(cookie)
+	engine.ServeHTTP(rec, req)
+	for _, c := range rec.Result().Cookies() {
+		cookie = c
+	}
+
+	// Second read: the previous request already read (and thus cleared) it.
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/read", nil)
+	req.AddCookie(cookie)
+	engine.ServeHTTP(rec, req)
+
+	if len(readValues) != 2 {
+		t.Fatalf("want 2 reads recorded, got %d", len(readValues))
+	}
+	if readValues[0] != "saved!" {
+		t.Errorf("want flash still present on the request right after it was set, got %q", readValues[0])
+	}
+	if readValues[1] != "" {
+		t.Errorf("want flash gone on the request after it was read, got %q", readValues[1])
+	}
+}
+
+// TestFlashExpiresAfterReadThenExplicitCommit mirrors the JSONBodyTransport
+// doc's recommended pattern of calling GinAdapter.Commit directly to get a
+// synchronous token. A flash read right before that explicit Commit must
+// still be persisted as cleared, not just removed from the in-memory struct
+// and then silently dropped when the deferred commit later sees itself as
+// already done.
+func TestFlashExpiresAfterReadThenExplicitCommit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ga := New(newTestSessionManager(newCountingStore()))
+
+	_, engine := gin.CreateTestContext(httptest.NewRecorder())
+	engine.Use(ga.LoadAndSave)
+
+	var readValues []string
+	engine.GET("/set", func(c *gin.Context) {
+		ga.Flash(c, "notice", "saved!")
+		c.String(http.StatusOK, "ok")
+	})
+	engine.GET("/read-and-commit", func(c *gin.Context) {
+		readValues = append(readValues, ga.GetFlashString(c, "notice"))
+		if _, _, err := ga.Commit(c); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		c.String(http.StatusOK, "ok")
+	})
+	engine.GET("/read", func(c *gin.Context) {
+		readValues = append(readValues, ga.GetFlashString(c, "notice"))
+		c.String(http.StatusOK, "ok")
+	})
+
+	var cookie *http.Cookie
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/set", nil)
+	engine.ServeHTTP(rec, req)
+	for _, c := range rec.Result().Cookies() {
+		cookie = c
+	}
+	if cookie == nil {
+		t.Fatal("want a session cookie after setting a flash")
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/read-and-commit", nil)
+	req.AddCookie(cookie)
+	engine.ServeHTTP(rec, req)
+	for _, c := range rec.Result().Cookies() {
+		cookie = c
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/read", nil)
+	req.AddCookie(cookie)
+	engine.ServeHTTP(rec, req)
+
+	if len(readValues) != 2 {
+		t.Fatalf("want 2 reads recorded, got %d", len(readValues))
+	}
+	if readValues[0] != "saved!" {
+		t.Errorf("want flash present when read alongside an explicit Commit, got %q", readValues[0])
+	}
+	if readValues[1] != "" {
+		t.Errorf("want flash gone on the request after the explicit Commit, got %q", readValues[1])
+	}
+}
@@ -1,6 +1,9 @@
 package scs_gin_adapter
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/alexedwards/scs/v2"
@@ -9,26 +12,77 @@ import (
 
 // GinAdapter represents the session adapter.
 type GinAdapter struct {
-	sm *scs.SessionManager
+	sm        *scs.SessionManager
+	transport TokenTransport
+	sessions  map[string]*GinAdapter
 }
 
-// New returns a new GinAdapter instance that embeds the original SCS session manager.
+// New returns a new GinAdapter instance that embeds the original SCS session
+// manager and communicates the session token via a cookie, matching the
+// behaviour of upstream SCS's own LoadAndSave middleware.
 func New(s *scs.SessionManager) *GinAdapter {
-	return &GinAdapter{s}
+	return NewWithTransport(s, NewCookieTransport(s))
 }
 
-// LoadAndSave provides a Gin middleware which automatically loads and saves session
-// data for the current request, and communicates the session token to and from
-// the client in a cookie.
+// NewWithTransport returns a new GinAdapter instance that embeds the original
+// SCS session manager and communicates the session token using the given
+// TokenTransport, e.g. a HeaderTransport or JSONBodyTransport for clients
+// that cannot rely on cookies.
+func NewWithTransport(s *scs.SessionManager, transport TokenTransport) *GinAdapter {
+	return &GinAdapter{sm: s, transport: transport}
+}
+
+// Register adds a named sub-session to the adapter, backed by its own
+// SessionManager and communicated via its own cookie. This lets a single
+// Gin app cleanly separate concerns — e.g. short-lived flash/cart state
+// from long-lived auth state — without stacking multiple LoadAndSave
+// middlewares. Use Session to access a registered sub-session from a
+// handler.
+func (ga *GinAdapter) Register(name string, s *scs.SessionManager) {
+	ga.RegisterWithTransport(name, s, NewCookieTransport(s))
+}
+
+// RegisterWithTransport is like Register but lets the sub-session use a
+// TokenTransport other than the cookie-based default.
+func (ga *GinAdapter) RegisterWithTransport(name string, s *scs.SessionManager, transport TokenTransport) {
+	if ga.sessions == nil {
+		ga.sessions = make(map[string]*GinAdapter)
+	}
+	ga.sessions[name] = NewWithTransport(s, transport)
+}
+
+// Session returns the sub-session adapter registered under name, so it can
+// be used exactly like the primary adapter, e.g.
+// ga.Session(ctx, "cart").Put(ctx, "items", cart). It panics if name was
+// never registered via Register or RegisterWithTransport.
+func (ga *GinAdapter) Session(ctx *gin.Context, name string) *GinAdapter {
+	sub, ok := ga.sessions[name]
+	if !ok {
+		panic("scs_gin_adapter: no session registered with name " + name)
+	}
+	return sub
+}
+
+// LoadAndSave provides a Gin middleware which automatically loads and saves
+// session data for the current request, and communicates the session token
+// to and from the client via the adapter's TokenTransport. Any sub-sessions
+// added with Register are loaded and committed alongside the primary
+// session, each via its own TokenTransport.
+//
+// Unlike calling Commit after every mutating helper, LoadAndSave defers the
+// commit to the point the response actually starts being written. Each
+// session's own status (Modified/Destroyed/Unmodified) is used to decide
+// whether a commit is needed at all, so a request that never touches a
+// given session performs no extra session store write for it. The deferred
+// commit is wired up through a wrapping ResponseWriter so it still fires
+// correctly for streamed responses (first Write) and hijacked connections
+// (Hijack), and a final call after ginCtx.Next() covers handlers that never
+// write a single byte.
 func (ga *GinAdapter) LoadAndSave(ginCtx *gin.Context) {
 	respWriter := ginCtx.Writer
 	req := ginCtx.Request
 
-	var token string
-	cookie, err := req.Cookie(ga.sm.Cookie.Name)
-	if err == nil {
-		token = cookie.Value
-	}
+	token, _ := ga.transport.Extract(ginCtx)
 
 	ctx, err := ga.sm.Load(req.Context(), token)
 	if err != nil {
@@ -36,20 +90,98 @@ func (ga *GinAdapter) LoadAndSave(ginCtx *gin.Context) {
 		return
 	}
 
+	for _, sub := range ga.sessions {
+		subToken, _ := sub.transport.Extract(ginCtx)
+		ctx, err = sub.sm.Load(ctx, subToken)
+		if err != nil {
+			sub.sm.ErrorFunc(respWriter, req, err)
+			return
+		}
+	}
+
 	sessionReq := req.WithContext(ctx)
 	respWriter.Header().Add("Vary", "Cookie")
 
+	cw := &commitResponseWriter{ResponseWriter: respWriter}
+	cw.commit = func() {
+		ga.commitOne(ginCtx, ctx, req, cw)
+		for _, sub := range ga.sessions {
+			sub.commitOne(ginCtx, ctx, req, cw)
+		}
+	}
+
+	ginCtx.Writer = cw
 	ginCtx.Request = sessionReq
 	ginCtx.Next()
+
+	cw.commitOnce()
+}
+
+// commitOne commits ga's own session (not its sub-sessions) against the
+// shared session context ctx, writing the token via ga's transport. Any
+// flash entries read during the request are removed first, so they do not
+// survive into a second request. If ga's session was already committed
+// synchronously via Commit earlier in the request, this is a no-op: the
+// underlying SCS status is still Modified at this point (Commit does not
+// reset it), but committing again would write to the store a second time
+// and re-invoke the transport for no reason.
+func (ga *GinAdapter) commitOne(ginCtx *gin.Context, ctx context.Context, req *http.Request, errWriter http.ResponseWriter) {
+	ga.clearReadFlashes(ginCtx, ctx)
+
+	if _, done := ginCtx.Get(ga.committedContextKey()); done {
+		return
+	}
+
+	switch ga.sm.Status(ctx) {
+	case scs.Modified:
+		tok, exp, err := ga.sm.Commit(ctx)
+		if err != nil {
+			ga.sm.ErrorFunc(errWriter, req, err)
+			return
+		}
+		ga.transport.Commit(ginCtx, tok, exp)
+	case scs.Destroyed:
+		ga.transport.Commit(ginCtx, "", time.Time{})
+	}
+}
+
+// committedContextKey returns the gin.Context key Commit uses to record
+// that it already committed ga's session synchronously this request, scoped
+// to ga's own SessionManager so sub-sessions each track this independently.
+func (ga *GinAdapter) committedContextKey() string {
+	return fmt.Sprintf("scs_gin_adapter.committed.%p", ga.sm)
+}
+
+// Commit immediately saves the session data to the store, writes its token
+// via ga's TokenTransport and returns the token and expiry, bypassing
+// LoadAndSave's deferred commit. Handlers using
+// JSONBodyTransport need this to get a real token to embed in their own
+// response body, since by the time the deferred commit runs the response
+// has already been written. LoadAndSave's deferred commit still runs
+// afterwards, but it is marked as already done for this session so it does
+// not commit to the store (and invoke the transport) a second time. Any
+// flash entries read so far are cleared before the save, the same as the
+// deferred commit does, so a flash read right before an explicit Commit
+// still expires after this request.
+func (ga *GinAdapter) Commit(ctx *gin.Context) (string, time.Time, error) {
+	ga.clearReadFlashes(ctx, ctx.Request.Context())
+
+	tok, exp, err := ga.sm.Commit(ctx.Request.Context())
+	if err != nil {
+		return tok, exp, err
+	}
+	ctx.Set(ga.committedContextKey(), true)
+	if err := ga.transport.Commit(ctx, tok, exp); err != nil {
+		return tok, exp, err
+	}
+	return tok, exp, nil
 }
 
 // Put adds a key and corresponding value to the session data. Any existing
 // value for the key will be replaced. The session data status will be set to
-// Modified.
+// Modified, and will be committed once the response starts being written.
 func (ga *GinAdapter) Put(ctx *gin.Context, key string, val interface{}) {
 	ga.sm.Put(ctx.Request.Context(), key, val)
-	tok, exp, _ := ga.sm.Commit(ctx.Request.Context())
-	ga.sm.WriteSessionCookie(ctx.Request.Context(), ctx.Writer, tok, exp)
 }
 
 // Get returns the value for a given key from the session data. The return
@@ -64,9 +196,7 @@ func (ga *GinAdapter) Put(ctx *gin.Context, key string, val interface{}) {
 // Also see the GetString(), GetInt(), GetBytes() and other helper methods which
 // wrap the type conversion for common types.
 func (ga *GinAdapter) Get(ctx *gin.Context, key string) interface{} {
-	val := ga.sm.Get(ctx.Request.Context(), key)
-	ga.sm.Commit(ctx.Request.Context())
-	return val
+	return ga.sm.Get(ctx.Request.Context(), key)
 }
 
 // Remove deletes the given key and corresponding value from the session data.
@@ -74,21 +204,14 @@ func (ga *GinAdapter) Get(ctx *gin.Context, key string) interface{} {
 // this operation is a no-op.
 func (ga *GinAdapter) Remove(ctx *gin.Context, key string) {
 	ga.sm.Remove(ctx.Request.Context(), key)
-	tok, exp, _ := ga.sm.Commit(ctx.Request.Context())
-	ga.sm.WriteSessionCookie(ctx.Request.Context(), ctx.Writer, tok, exp)
-	return
 }
 
 // Destroy deletes the session data from the session store and sets the session
 // status to Destroyed. Any further operations in the same request cycle will
-// result in a new session being created.
+// result in a new session being created. The client's session token is
+// cleared once the response starts being written.
 func (ga *GinAdapter) Destroy(ctx *gin.Context) error {
-	err := ga.sm.Destroy(ctx.Request.Context())
-	if err != nil {
-		return err
-	}
-	ga.sm.WriteSessionCookie(ctx.Request.Context(), ctx.Writer, "", time.Time{})
-	return nil
+	return ga.sm.Destroy(ctx.Request.Context())
 }
 
 // RenewToken updates the session data to have a new session token while
@@ -102,13 +225,7 @@ func (ga *GinAdapter) Destroy(ctx *gin.Context) error {
 // logout operations). See https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/Session_Management_Cheat_Sheet.md#renew-the-session-id-after-any-privilege-level-change
 // for additional information.
 func (ga *GinAdapter) RenewToken(ctx *gin.Context) error {
-	err := ga.sm.RenewToken(ctx.Request.Context())
-	if err != nil {
-		return err
-	}
-	tok, exp, _ := ga.sm.Commit(ctx.Request.Context())
-	ga.sm.WriteSessionCookie(ctx.Request.Context(), ctx.Writer, tok, exp)
-	return nil
+	return ga.sm.RenewToken(ctx.Request.Context())
 }
 
 // RememberMe controls whether the session cookie is persistent (i.e  whether it
@@ -117,16 +234,162 @@ func (ga *GinAdapter) RenewToken(ctx *gin.Context) error {
 // you are using the standard LoadAndSave() middleware.
 func (ga *GinAdapter) RememberMe(ctx *gin.Context, val bool) {
 	ga.sm.RememberMe(ctx.Request.Context(), val)
-	tok, exp, _ := ga.sm.Commit(ctx.Request.Context())
-	ga.sm.WriteSessionCookie(ctx.Request.Context(), ctx.Writer, tok, exp)
 }
 
 // GetString returns the string value for a given key from the session data.
 // The zero value for a string ("") is returned if the key does not exist or the
 // value could not be type asserted to a string.
 func (ga *GinAdapter) GetString(ctx *gin.Context, key string) string {
-	val := ga.sm.GetString(ctx.Request.Context(), key)
-	tok, exp, _ := ga.sm.Commit(ctx.Request.Context())
-	ga.sm.WriteSessionCookie(ctx.Request.Context(), ctx.Writer, tok, exp)
+	return ga.sm.GetString(ctx.Request.Context(), key)
+}
+
+// GetInt returns the int value for a given key from the session data. The
+// zero value for an int (0) is returned if the key does not exist or the
+// value could not be type asserted to an int.
+func (ga *GinAdapter) GetInt(ctx *gin.Context, key string) int {
+	return ga.sm.GetInt(ctx.Request.Context(), key)
+}
+
+// GetInt64 returns the int64 value for a given key from the session data. The
+// zero value for an int64 (0) is returned if the key does not exist or the
+// value could not be type asserted to an int64.
+func (ga *GinAdapter) GetInt64(ctx *gin.Context, key string) int64 {
+	return ga.sm.GetInt64(ctx.Request.Context(), key)
+}
+
+// GetFloat returns the float64 value for a given key from the session data.
+// The zero value for a float64 (0) is returned if the key does not exist or
+// the value could not be type asserted to a float64.
+func (ga *GinAdapter) GetFloat(ctx *gin.Context, key string) float64 {
+	return ga.sm.GetFloat(ctx.Request.Context(), key)
+}
+
+// GetBool returns the bool value for a given key from the session data. The
+// zero value for a bool (false) is returned if the key does not exist or the
+// value could not be type asserted to a bool.
+func (ga *GinAdapter) GetBool(ctx *gin.Context, key string) bool {
+	return ga.sm.GetBool(ctx.Request.Context(), key)
+}
+
+// GetBytes returns the byte slice ([]byte) value for a given key from the
+// session data. The zero value for a byte slice (nil) is returned if the key
+// does not exist or could not be type asserted to []byte.
+func (ga *GinAdapter) GetBytes(ctx *gin.Context, key string) []byte {
+	return ga.sm.GetBytes(ctx.Request.Context(), key)
+}
+
+// GetTime returns the time.Time value for a given key from the session data.
+// The zero value for a time.Time object is returned if the key does not
+// exist or the value could not be type asserted to a time.Time.
+func (ga *GinAdapter) GetTime(ctx *gin.Context, key string) time.Time {
+	return ga.sm.GetTime(ctx.Request.Context(), key)
+}
+
+// Pop acts like a one-time Get. It returns the value for a given key from
+// the session data and then deletes it. The session data status will be set
+// to Modified if the key exists.
+func (ga *GinAdapter) Pop(ctx *gin.Context, key string) interface{} {
+	return ga.sm.Pop(ctx.Request.Context(), key)
+}
+
+// PopString acts like a one-time GetString. It returns the string value for
+// a given key from the session data and then deletes it. The session data
+// status will be set to Modified if the key exists.
+func (ga *GinAdapter) PopString(ctx *gin.Context, key string) string {
+	return ga.sm.PopString(ctx.Request.Context(), key)
+}
+
+// PopInt acts like a one-time GetInt. It returns the int value for a given
+// key from the session data and then deletes it. The session data status
+// will be set to Modified if the key exists.
+func (ga *GinAdapter) PopInt(ctx *gin.Context, key string) int {
+	return ga.sm.PopInt(ctx.Request.Context(), key)
+}
+
+// PopInt64 acts like a one-time GetInt64. It returns the int64 value for a
+// given key from the session data and then deletes it. The session data
+// status will be set to Modified if the key exists. SCS itself has no
+// PopInt64, so this is implemented directly on top of Pop, the same way SCS's
+// own GetInt64 is implemented on top of Get.
+func (ga *GinAdapter) PopInt64(ctx *gin.Context, key string) int64 {
+	val, _ := ga.sm.Pop(ctx.Request.Context(), key).(int64)
 	return val
 }
+
+// PopFloat acts like a one-time GetFloat. It returns the float64 value for a
+// given key from the session data and then deletes it. The session data
+// status will be set to Modified if the key exists.
+func (ga *GinAdapter) PopFloat(ctx *gin.Context, key string) float64 {
+	return ga.sm.PopFloat(ctx.Request.Context(), key)
+}
+
+// PopBool acts like a one-time GetBool. It returns the bool value for a
+// given key from the session data and then deletes it. The session data
+// status will be set to Modified if the key exists.
+func (ga *GinAdapter) PopBool(ctx *gin.Context, key string) bool {
+	return ga.sm.PopBool(ctx.Request.Context(), key)
+}
+
+// PopBytes acts like a one-time GetBytes. It returns the byte slice
+// ([]byte) value for a given key from the session data and then deletes it.
+// The session data status will be set to Modified if the key exists.
+func (ga *GinAdapter) PopBytes(ctx *gin.Context, key string) []byte {
+	return ga.sm.PopBytes(ctx.Request.Context(), key)
+}
+
+// PopTime acts like a one-time GetTime. It returns the time.Time value for a
+// given key from the session data and then deletes it. The session data
+// status will be set to Modified if the key exists.
+func (ga *GinAdapter) PopTime(ctx *gin.Context, key string) time.Time {
+	return ga.sm.PopTime(ctx.Request.Context(), key)
+}
+
+// Exists returns true if the given key is present in the session data.
+func (ga *GinAdapter) Exists(ctx *gin.Context, key string) bool {
+	return ga.sm.Exists(ctx.Request.Context(), key)
+}
+
+// Keys returns a slice of all key names present in the session data, sorted
+// alphabetically.
+func (ga *GinAdapter) Keys(ctx *gin.Context) []string {
+	return ga.sm.Keys(ctx.Request.Context())
+}
+
+// Clear removes all data for the current session. The session token and
+// lifetime are unaffected. The session data status will be set to Modified.
+func (ga *GinAdapter) Clear(ctx *gin.Context) error {
+	return ga.sm.Clear(ctx.Request.Context())
+}
+
+// Iterate retrieves all active sessions from the store and executes the fn
+// function for each session, passing a context containing the session data.
+// It is intended to be used for bulk operations across every session in the
+// store and does not affect the current request's session.
+func (ga *GinAdapter) Iterate(ctx *gin.Context, fn func(context.Context) error) error {
+	return ga.sm.Iterate(ctx.Request.Context(), fn)
+}
+
+// Status returns the current status of the session data.
+func (ga *GinAdapter) Status(ctx *gin.Context) scs.Status {
+	return ga.sm.Status(ctx.Request.Context())
+}
+
+// Token returns the session token. Note that this will return the empty
+// string until LoadAndSave has loaded the session and a session token has
+// been committed.
+func (ga *GinAdapter) Token(ctx *gin.Context) string {
+	return ga.sm.Token(ctx.Request.Context())
+}
+
+// Deadline returns the time when the session data will expire. This is not
+// the same as the expiry time of the session cookie.
+func (ga *GinAdapter) Deadline(ctx *gin.Context) time.Time {
+	return ga.sm.Deadline(ctx.Request.Context())
+}
+
+// MergeSession is used to merge in data from a different session in case
+// strict session tokens are lost. The session data status will be set to
+// Modified if any changes are made.
+func (ga *GinAdapter) MergeSession(ctx *gin.Context, token string) error {
+	return ga.sm.MergeSession(ctx.Request.Context(), token)
+}
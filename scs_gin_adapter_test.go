@@ -0,0 +1,135 @@
+package scs_gin_adapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// countingStore is a minimal in-memory scs.Store that counts how many times
+// Commit is called, so tests can assert on the number of session-store
+// writes a request performs.
+type countingStore struct {
+	mu      sync.Mutex
+	commits int
+	data    map[string][]byte
+}
+
+func newCountingStore() *countingStore {
+	return &countingStore{data: make(map[string][]byte)}
+}
+
+func (s *countingStore) Delete(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, token)
+	return nil
+}
+
+func (s *countingStore) Find(token string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.data[token]
+	return b, ok, nil
+}
+
+func (s *countingStore) Commit(token string, b []byte, expiry time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commits++
+	s.data[token] = b
+	return nil
+}
+
+func (s *countingStore) commitCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commits
+}
+
+func newTestSessionManager(store scs.Store) *scs.SessionManager {
+	sm := scs.New()
+	sm.Store = store
+	return sm
+}
+
+func TestLoadAndSaveCommitsEachSessionOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	primaryStore := newCountingStore()
+	cartStore := newCountingStore()
+
+	ga := New(newTestSessionManager(primaryStore))
+	ga.Register("cart", newTestSessionManager(cartStore))
+
+	rec := httptest.NewRecorder()
+	ginCtx, engine := gin.CreateTestContext(rec)
+	engine.Use(ga.LoadAndSave)
+	engine.GET("/", func(c *gin.Context) {
+		ga.Put(c, "foo", "bar")
+		ga.Session(c, "cart").Put(c, "items", 2)
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ginCtx.Request = req
+	engine.ServeHTTP(rec, req)
+
+	if primaryStore.commitCount() != 1 {
+		t.Errorf("want primary session committed once, got %d", primaryStore.commitCount())
+	}
+	if cartStore.commitCount() != 1 {
+		t.Errorf("want cart sub-session committed once, got %d", cartStore.commitCount())
+	}
+}
+
+func TestLoadAndSaveSkipsUnmodifiedSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	primaryStore := newCountingStore()
+	ga := New(newTestSessionManager(primaryStore))
+
+	rec := httptest.NewRecorder()
+	_, engine := gin.CreateTestContext(rec)
+	engine.Use(ga.LoadAndSave)
+	engine.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	engine.ServeHTTP(rec, req)
+
+	if primaryStore.commitCount() != 0 {
+		t.Errorf("want no commit for an untouched session, got %d", primaryStore.commitCount())
+	}
+}
+
+func TestExplicitCommitIsNotRepeatedByLoadAndSave(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newCountingStore()
+	ga := New(newTestSessionManager(store))
+
+	rec := httptest.NewRecorder()
+	_, engine := gin.CreateTestContext(rec)
+	engine.Use(ga.LoadAndSave)
+	engine.GET("/", func(c *gin.Context) {
+		ga.Put(c, "foo", "bar")
+		if _, _, err := ga.Commit(c); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	engine.ServeHTTP(rec, req)
+
+	if got := store.commitCount(); got != 1 {
+		t.Errorf("want exactly 1 store write for an explicit Commit, got %d", got)
+	}
+}
@@ -0,0 +1,154 @@
+package scs_gin_adapter
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// ErrNoToken is returned by a TokenTransport's Extract method when no
+// session token is present in the request. LoadAndSave treats this the same
+// way as a missing cookie: the session simply starts out empty.
+var ErrNoToken = errors.New("scs_gin_adapter: no session token in request")
+
+// TokenTransport describes how the session token is communicated between
+// the client and the server. The default, CookieTransport, mirrors upstream
+// SCS's own cookie-based LoadAndSave middleware. HeaderTransport and
+// JSONBodyTransport are provided for SPA/mobile clients and CORS-restricted
+// flows where cookies are impractical.
+type TokenTransport interface {
+	// Extract retrieves the session token from the incoming request. It
+	// returns ErrNoToken (or a wrapped form of it) if no token is present.
+	Extract(ginCtx *gin.Context) (string, error)
+	// Commit writes the session token and its expiry time to the response,
+	// or clears it from the client if token is the empty string.
+	Commit(ginCtx *gin.Context, token string, expiry time.Time) error
+}
+
+// CookieTransport communicates the session token via the cookie configured
+// on the underlying SCS session manager. This is the transport used by New.
+type CookieTransport struct {
+	sm *scs.SessionManager
+}
+
+// NewCookieTransport returns a CookieTransport that reads and writes the
+// session token using sm's configured cookie settings.
+func NewCookieTransport(sm *scs.SessionManager) *CookieTransport {
+	return &CookieTransport{sm: sm}
+}
+
+// Extract implements TokenTransport.
+func (t *CookieTransport) Extract(ginCtx *gin.Context) (string, error) {
+	cookie, err := ginCtx.Request.Cookie(t.sm.Cookie.Name)
+	if err != nil {
+		return "", ErrNoToken
+	}
+	return cookie.Value, nil
+}
+
+// Commit implements TokenTransport.
+func (t *CookieTransport) Commit(ginCtx *gin.Context, token string, expiry time.Time) error {
+	t.sm.WriteSessionCookie(ginCtx.Request.Context(), ginCtx.Writer, token, expiry)
+	return nil
+}
+
+// HeaderTransport communicates the session token via a request/response
+// header, e.g. "X-Session-Token". This suits mobile or SPA clients that
+// manage the token themselves rather than relying on cookies.
+type HeaderTransport struct {
+	// HeaderName is the header used to carry the session token in both
+	// directions. NewHeaderTransport sets it to "X-Session-Token" when given
+	// an empty name; Extract and Commit use it as is.
+	HeaderName string
+}
+
+// NewHeaderTransport returns a HeaderTransport that reads and writes the
+// session token using headerName. If headerName is empty, "X-Session-Token"
+// is used.
+func NewHeaderTransport(headerName string) *HeaderTransport {
+	if headerName == "" {
+		headerName = "X-Session-Token"
+	}
+	return &HeaderTransport{HeaderName: headerName}
+}
+
+// Extract implements TokenTransport.
+func (t *HeaderTransport) Extract(ginCtx *gin.Context) (string, error) {
+	token := ginCtx.GetHeader(t.HeaderName)
+	if token == "" {
+		return "", ErrNoToken
+	}
+	return token, nil
+}
+
+// Commit implements TokenTransport. The expiry is ignored, since an HTTP
+// header carries no expiry semantics of its own; the client is expected to
+// discard the token once the server reports the session as invalid.
+func (t *HeaderTransport) Commit(ginCtx *gin.Context, token string, _ time.Time) error {
+	ginCtx.Header(t.HeaderName, token)
+	return nil
+}
+
+// JSONBodyToken is the shape JSONBodyTransport expects in the request body
+// and echoes back to the caller via the gin.Context.
+type JSONBodyToken struct {
+	Token string `json:"session_token"`
+}
+
+// SessionTokenContextKey is the gin.Context key JSONBodyTransport uses to
+// expose the committed token, so that a handler can merge it into its own
+// JSON response body.
+const SessionTokenContextKey = "scs_session_token"
+
+// SessionExpiryContextKey is the gin.Context key JSONBodyTransport uses to
+// expose the committed session expiry time.
+const SessionExpiryContextKey = "scs_session_expiry"
+
+// JSONBodyTransport communicates the session token via the request and
+// response bodies, for clients that prefer to keep the token out of cookies
+// and headers entirely. Since Gin handlers own their own response bodies,
+// JSONBodyTransport does not write the response itself; instead it stashes
+// the committed token and expiry on the gin.Context under
+// SessionTokenContextKey and SessionExpiryContextKey, and a handler that
+// needs the token to build its own JSON payload should call
+// GinAdapter.Commit(ctx) directly to get it synchronously, rather than
+// relying on LoadAndSave's deferred commit, which only runs once the
+// response has already started being written.
+type JSONBodyTransport struct{}
+
+// NewJSONBodyTransport returns a JSONBodyTransport.
+func NewJSONBodyTransport() *JSONBodyTransport {
+	return &JSONBodyTransport{}
+}
+
+// Extract implements TokenTransport. It reads a JSONBodyToken from the
+// request body without consuming it, so the body remains available to the
+// handler.
+func (t *JSONBodyTransport) Extract(ginCtx *gin.Context) (string, error) {
+	var body JSONBodyToken
+	err := ginCtx.ShouldBindBodyWith(&body, binding.JSON)
+	// ShouldBindBodyWith drains Request.Body into a cache on the gin.Context;
+	// put the cached bytes back so the handler can still read the body.
+	if cached, ok := ginCtx.Get(gin.BodyBytesKey); ok {
+		if b, ok := cached.([]byte); ok {
+			ginCtx.Request.Body = io.NopCloser(bytes.NewReader(b))
+		}
+	}
+	if err != nil || body.Token == "" {
+		return "", ErrNoToken
+	}
+	return body.Token, nil
+}
+
+// Commit implements TokenTransport. It stashes the token and expiry on the
+// gin.Context for the handler to surface in its own response body.
+func (t *JSONBodyTransport) Commit(ginCtx *gin.Context, token string, expiry time.Time) error {
+	ginCtx.Set(SessionTokenContextKey, token)
+	ginCtx.Set(SessionExpiryContextKey, expiry)
+	return nil
+}
@@ -0,0 +1,176 @@
+package scs_gin_adapter
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestCookieTransportRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sm := newTestSessionManager(newCountingStore())
+	transport := NewCookieTransport(sm)
+
+	rec := httptest.NewRecorder()
+	ginCtx, _ := gin.CreateTestContext(rec)
+	ginCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	expiry := time.Now().Add(time.Hour).UTC()
+	if err := transport.Commit(ginCtx, "tok-123", expiry); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	var cookie *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == sm.Cookie.Name {
+			cookie = c
+		}
+	}
+	if cookie == nil {
+		t.Fatal("want a session cookie to be set")
+	}
+
+	extractCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	extractCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	extractCtx.Request.AddCookie(cookie)
+
+	got, err := transport.Extract(extractCtx)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if got != "tok-123" {
+		t.Errorf("want extracted token %q, got %q", "tok-123", got)
+	}
+}
+
+func TestCookieTransportExtractNoToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sm := newTestSessionManager(newCountingStore())
+	transport := NewCookieTransport(sm)
+
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if _, err := transport.Extract(ginCtx); err != ErrNoToken {
+		t.Errorf("want ErrNoToken, got %v", err)
+	}
+}
+
+func TestHeaderTransportRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	transport := NewHeaderTransport("")
+	if transport.HeaderName != "X-Session-Token" {
+		t.Fatalf("want default header name X-Session-Token, got %q", transport.HeaderName)
+	}
+
+	rec := httptest.NewRecorder()
+	ginCtx, _ := gin.CreateTestContext(rec)
+	ginCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if err := transport.Commit(ginCtx, "tok-456", time.Now()); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if got := rec.Header().Get("X-Session-Token"); got != "tok-456" {
+		t.Errorf("want response header set to %q, got %q", "tok-456", got)
+	}
+
+	extractCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	extractCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	extractCtx.Request.Header.Set("X-Session-Token", "tok-456")
+
+	got, err := transport.Extract(extractCtx)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if got != "tok-456" {
+		t.Errorf("want extracted token %q, got %q", "tok-456", got)
+	}
+}
+
+func TestHeaderTransportExtractNoToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	transport := NewHeaderTransport("X-Custom-Token")
+
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if _, err := transport.Extract(ginCtx); err != ErrNoToken {
+		t.Errorf("want ErrNoToken, got %v", err)
+	}
+}
+
+func TestJSONBodyTransportRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	transport := NewJSONBodyTransport()
+
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"session_token":"tok-789"}`))
+	ginCtx.Request.Header.Set("Content-Type", "application/json")
+
+	got, err := transport.Extract(ginCtx)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if got != "tok-789" {
+		t.Errorf("want extracted token %q, got %q", "tok-789", got)
+	}
+
+	// Extract uses ShouldBindBodyWith, which caches the body on the
+	// gin.Context, so a handler binding its own payload the same way (e.g.
+	// ShouldBindBodyWith(&payload, binding.JSON)) still sees the full body.
+	var payload JSONBodyToken
+	if err := ginCtx.ShouldBindBodyWith(&payload, binding.JSON); err != nil {
+		t.Fatalf("handler's own ShouldBindBodyWith: %v", err)
+	}
+	if payload.Token != "tok-789" {
+		t.Errorf("want handler to still see token %q in the body, got %q", "tok-789", payload.Token)
+	}
+}
+
+func TestJSONBodyTransportExtractNoToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	transport := NewJSONBodyTransport()
+
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{}`))
+	ginCtx.Request.Header.Set("Content-Type", "application/json")
+
+	if _, err := transport.Extract(ginCtx); err != ErrNoToken {
+		t.Errorf("want ErrNoToken, got %v", err)
+	}
+}
+
+func TestJSONBodyTransportCommitStashesContextKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	transport := NewJSONBodyTransport()
+
+	ginCtx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ginCtx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	expiry := time.Now().Add(time.Hour)
+	if err := transport.Commit(ginCtx, "tok-789", expiry); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	tok, ok := ginCtx.Get(SessionTokenContextKey)
+	if !ok || tok != "tok-789" {
+		t.Errorf("want %s stashed as %q, got %v (ok=%v)", SessionTokenContextKey, "tok-789", tok, ok)
+	}
+
+	exp, ok := ginCtx.Get(SessionExpiryContextKey)
+	if !ok || !exp.(time.Time).Equal(expiry) {
+		t.Errorf("want %s stashed as %v, got %v (ok=%v)", SessionExpiryContextKey, expiry, exp, ok)
+	}
+}